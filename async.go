@@ -0,0 +1,126 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithAsync makes the logger write asynchronously: each pre-encoded entry is
+// pushed onto a bounded channel drained by a background goroutine, so
+// callers never block on the underlying writer's I/O. If the channel is
+// full, the entry is dropped rather than blocking the caller, and onDrop
+// (if non-nil) is called with the running drop count. Call Flush or Close
+// to drain before shutdown.
+func WithAsync(bufSize int, onDrop func(dropped uint64)) LoggerOption {
+	return func(l *Logger) {
+		l.asyncEnabled = true
+		l.asyncBufSize = bufSize
+		l.asyncOnDrop = onDrop
+	}
+}
+
+type asyncWriter struct {
+	w      io.Writer
+	ch     chan []byte
+	onDrop func(dropped uint64)
+
+	dropped uint64
+
+	// pending counts entries that have been enqueued but not yet fully
+	// written by run, so flush can wait on actual completion rather than
+	// inferring it from channel length (which drops to zero the moment an
+	// entry is dequeued, before its Write call returns).
+	pending int64
+
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func newAsyncWriter(w io.Writer, bufSize int, onDrop func(dropped uint64)) *asyncWriter {
+	a := &asyncWriter{
+		w:      w,
+		ch:     make(chan []byte, bufSize),
+		onDrop: onDrop,
+	}
+
+	a.wg.Add(1)
+	go a.run()
+
+	return a
+}
+
+func (a *asyncWriter) run() {
+	defer a.wg.Done()
+
+	for b := range a.ch {
+		_, _ = a.w.Write(b) // nolint: errcheck
+		atomic.AddInt64(&a.pending, -1)
+	}
+}
+
+func (a *asyncWriter) write(b []byte) {
+	select {
+	case a.ch <- b:
+		atomic.AddInt64(&a.pending, 1)
+	default:
+		dropped := atomic.AddUint64(&a.dropped, 1)
+		if a.onDrop != nil {
+			a.onDrop(dropped)
+		}
+	}
+}
+
+// flush blocks until every entry enqueued so far has actually been written
+// to the underlying writer, or ctx is done.
+func (a *asyncWriter) flush(ctx context.Context) error {
+	done := make(chan struct{})
+
+	go func() {
+		for atomic.LoadInt64(&a.pending) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close flushes, then stops the background goroutine and waits for it to
+// exit.
+func (a *asyncWriter) close(ctx context.Context) error {
+	err := a.flush(ctx)
+
+	a.closeOnce.Do(func() {
+		close(a.ch)
+	})
+	a.wg.Wait()
+
+	return err
+}
+
+// Flush blocks until all entries buffered by WithAsync have been written to
+// the underlying writer, or ctx is done. It is a no-op when async writing
+// isn't enabled.
+func (l *Logger) Flush(ctx context.Context) error {
+	if l.async == nil {
+		return nil
+	}
+	return l.async.flush(ctx)
+}
+
+// Close flushes and stops the background writer goroutine started by
+// WithAsync. It is a no-op when async writing isn't enabled.
+func (l *Logger) Close() error {
+	if l.async == nil {
+		return nil
+	}
+	return l.async.close(context.Background())
+}
@@ -0,0 +1,59 @@
+package log
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Color modes accepted by WithColor.
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// WithColor controls whether ConsoleEncoder emits ANSI color escapes around
+// the level token. mode is one of ColorAuto (detect whether the writer is a
+// terminal), ColorAlways, or ColorNever. Detection also respects NO_COLOR.
+// It only has an effect when the logger's encoder is a ConsoleEncoder.
+func WithColor(mode string) LoggerOption {
+	return func(l *Logger) {
+		l.colorMode = mode
+	}
+}
+
+// isTerminal reports whether f is a terminal. It's a package var so tests
+// can stub it without requiring a real TTY.
+var isTerminal = func(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// resolveColor decides, once a Logger's options have all been applied,
+// whether its ConsoleEncoder (if any) should emit color.
+func resolveColor(mode string, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	if !isTerminal(f) {
+		return false
+	}
+
+	enableVirtualTerminal(f)
+
+	return true
+}
@@ -0,0 +1,55 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestResolveColorAlwaysAndNever(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	if !resolveColor(ColorAlways, &buf) {
+		t.Fatal("ColorAlways should force color on regardless of the writer")
+	}
+
+	if resolveColor(ColorNever, &buf) {
+		t.Fatal("ColorNever should force color off regardless of the writer")
+	}
+}
+
+func TestResolveColorAutoNonTerminalWriter(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	if resolveColor(ColorAuto, &buf) {
+		t.Fatal("auto mode should not colorize a non-*os.File writer")
+	}
+}
+
+func TestResolveColorAutoRespectsNOColor(t *testing.T) {
+
+	prev := isTerminal
+	isTerminal = func(*os.File) bool { return true }
+	defer func() { isTerminal = prev }()
+
+	t.Setenv("NO_COLOR", "1")
+
+	if resolveColor(ColorAuto, os.Stdout) {
+		t.Fatal("auto mode should not colorize when NO_COLOR is set, even on a terminal")
+	}
+}
+
+func TestResolveColorAutoDetectsTerminal(t *testing.T) {
+
+	prev := isTerminal
+	isTerminal = func(*os.File) bool { return true }
+	defer func() { isTerminal = prev }()
+
+	t.Setenv("NO_COLOR", "")
+
+	if !resolveColor(ColorAuto, os.Stdout) {
+		t.Fatal("auto mode should colorize a detected terminal writer")
+	}
+}
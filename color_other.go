@@ -0,0 +1,9 @@
+//go:build !windows
+
+package log
+
+import "os"
+
+// enableVirtualTerminal is a no-op outside Windows, where ANSI escapes are
+// already understood natively.
+func enableVirtualTerminal(_ *os.File) {}
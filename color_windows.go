@@ -0,0 +1,25 @@
+//go:build windows
+
+package log
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// enableVirtualTerminal turns on ENABLE_VIRTUAL_TERMINAL_PROCESSING for f's
+// console handle so ANSI escape sequences render in cmd.exe and legacy
+// Windows Terminal hosts. It is a best-effort call: failures are ignored,
+// matching how terminals that already support VT (or aren't consoles at
+// all) behave.
+func enableVirtualTerminal(f *os.File) {
+	handle := windows.Handle(f.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return
+	}
+
+	_ = windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING)
+}
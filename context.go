@@ -0,0 +1,57 @@
+package log
+
+import "context"
+
+// ContextExtractor pulls structured fields (e.g. OpenTelemetry trace_id/
+// span_id, or a request id) out of a context.Context as alternating
+// key/value pairs, for attachment to every entry logged through the *Ctx
+// methods below.
+type ContextExtractor func(ctx context.Context) []any
+
+// WithContextExtractor installs a ContextExtractor used by DebugCtx/
+// InfoCtx/WarnCtx/ErrorCtx to attach context-derived fields automatically,
+// so callers don't need to call With at every call site.
+func WithContextExtractor(extractor ContextExtractor) LoggerOption {
+	return func(l *Logger) {
+		l.ctxExtractor = extractor
+	}
+}
+
+// DebugCtx logs msg at DEBUG with fields pulled from ctx via the configured
+// ContextExtractor, alongside kv.
+func (l *Logger) DebugCtx(ctx context.Context, msg string, kv ...any) {
+	l.logCtx(ctx, DEBUG, msg, kv...)
+}
+
+// InfoCtx logs msg at INFO with fields pulled from ctx via the configured
+// ContextExtractor, alongside kv.
+func (l *Logger) InfoCtx(ctx context.Context, msg string, kv ...any) {
+	l.logCtx(ctx, INFO, msg, kv...)
+}
+
+// WarnCtx logs msg at WARN with fields pulled from ctx via the configured
+// ContextExtractor, alongside kv.
+func (l *Logger) WarnCtx(ctx context.Context, msg string, kv ...any) {
+	l.logCtx(ctx, WARN, msg, kv...)
+}
+
+// ErrorCtx logs msg at ERROR with fields pulled from ctx via the configured
+// ContextExtractor, alongside kv.
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, kv ...any) {
+	l.logCtx(ctx, ERROR, msg, kv...)
+}
+
+func (l *Logger) logCtx(ctx context.Context, level Level, msg string, kv ...any) {
+	var fields []any
+
+	if l.ctxExtractor != nil {
+		fields = append(fields, l.ctxExtractor(ctx)...)
+	}
+	fields = append(fields, kv...)
+
+	if err := ctx.Err(); err != nil {
+		fields = append(fields, "ctx_err", err.Error())
+	}
+
+	l.logw(level, msg, fields...)
+}
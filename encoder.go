@@ -0,0 +1,157 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Encoder formats an Entry into the bytes that get written to the
+// underlying writer. Encoders must not append the trailing newline; write
+// does that once for every encoder.
+type Encoder interface {
+	Encode(e Entry) ([]byte, error)
+}
+
+// WithEncoder overrides the default JSONEncoder used to format entries.
+func WithEncoder(enc Encoder) LoggerOption {
+	return func(l *Logger) {
+		l.encoder = enc
+	}
+}
+
+// JSONEncoder marshals the Entry as JSON, with structured fields nested
+// under a "fields" object. This is the default.
+type JSONEncoder struct{}
+
+// Encode implements Encoder.
+func (JSONEncoder) Encode(e Entry) ([]byte, error) {
+	return json.Marshal(&e)
+}
+
+// LogfmtEncoder renders the Entry as space-separated key=value pairs,
+// following the logfmt convention popularized by go-kit/log: values
+// containing spaces, '=', or '"' are quoted and escaped.
+type LogfmtEncoder struct{}
+
+// Encode implements Encoder.
+func (LogfmtEncoder) Encode(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writeLogfmtPair(&buf, "timestamp", e.Timestamp)
+	writeLogfmtPair(&buf, "level", e.Level)
+
+	if e.Name != "" {
+		writeLogfmtPair(&buf, "name", e.Name)
+	}
+	if e.Caller != "" {
+		writeLogfmtPair(&buf, "caller", e.Caller)
+	}
+
+	writeLogfmtPair(&buf, "msg", e.Message)
+
+	for _, k := range sortedKeys(e.Fields) {
+		writeLogfmtPair(&buf, k, fmt.Sprintf("%v", e.Fields[k]))
+	}
+
+	if e.Stacktrace != "" {
+		writeLogfmtPair(&buf, "stacktrace", e.Stacktrace)
+	}
+
+	return bytes.TrimRight(buf.Bytes(), " "), nil
+}
+
+func writeLogfmtPair(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	buf.WriteString(logfmtQuote(value))
+	buf.WriteByte(' ')
+}
+
+func logfmtQuote(v string) string {
+	if v == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(v, ` ="`) {
+		return v
+	}
+	return strconvQuote(v)
+}
+
+// ConsoleEncoder renders the Entry in a human-friendly line of the form
+// "TIME LEVEL name msg key=val ...", with the level token colorized unless
+// NoColor is set. When the encoder is installed with WithEncoder, New
+// replaces NoColor with the logger's color mode (WithColor, ColorAuto by
+// default), so the value given here is not kept.
+type ConsoleEncoder struct {
+	NoColor bool
+}
+
+// Encode implements Encoder.
+func (c ConsoleEncoder) Encode(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString(e.Timestamp)
+	buf.WriteByte(' ')
+
+	if c.NoColor {
+		buf.WriteString(e.Level)
+	} else {
+		buf.WriteString(string(colorFromLevel(levelFromString(e.Level))))
+		buf.WriteString(e.Level)
+		buf.WriteString(string(colorReset))
+	}
+	buf.WriteByte(' ')
+
+	if e.Name != "" {
+		buf.WriteString(e.Name)
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(e.Message)
+
+	for _, k := range sortedKeys(e.Fields) {
+		buf.WriteByte(' ')
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(logfmtQuote(fmt.Sprintf("%v", e.Fields[k])))
+	}
+
+	if e.Stacktrace != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(e.Stacktrace)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// strconvQuote quotes and escapes v the way Go source literals do, which is
+// sufficient for logfmt's quoting needs.
+func strconvQuote(v string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for _, r := range v {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\n':
+			buf.WriteString(`\n`)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchReopenSignal reopens fs whenever the process receives SIGHUP, the
+// convention logrotate and friends use to tell a long-running process its
+// log file was renamed out from under it. It returns a func that stops the
+// watch.
+func watchReopenSignal(fs *FileSink) func() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				fs.reopen() // nolint: errcheck
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package log
+
+// watchReopenSignal is a no-op on Windows, which has no SIGHUP equivalent.
+func watchReopenSignal(_ *FileSink) func() {
+	return func() {}
+}
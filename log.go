@@ -1,9 +1,9 @@
 package log
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"runtime/debug"
 	"strings"
@@ -17,14 +17,17 @@ type (
 
 	color string
 
-	entry struct {
-		Name       string `json:"name,omitempty"`
-		Level      string `json:"level"`
-		Caller     string `json:"caller,omitempty"`
-		Message    string `json:"msg,omitempty"`
-		Fields     []any  `json:"fields,omitempty"`
-		Stacktrace string `json:"stacktrace,omitempty"`
-		Timestamp  string `json:"timestamp"`
+	// Entry is the data an Encoder renders into the bytes written out for a
+	// single log call. It's exported so packages outside log can implement
+	// their own Encoder.
+	Entry struct {
+		Name       string         `json:"name,omitempty"`
+		Level      string         `json:"level"`
+		Caller     string         `json:"caller,omitempty"`
+		Message    string         `json:"msg,omitempty"`
+		Fields     map[string]any `json:"fields,omitempty"`
+		Stacktrace string         `json:"stacktrace,omitempty"`
+		Timestamp  string         `json:"timestamp"`
 	}
 )
 
@@ -137,7 +140,7 @@ func WithStacktrace(stacktrace bool) LoggerOption {
 
 // Logger
 type Logger struct {
-	mu sync.Mutex
+	mu *sync.Mutex
 	w  io.Writer
 
 	minLevel Level
@@ -146,19 +149,130 @@ type Logger struct {
 
 	includeCaller bool
 	stacktrace    bool
+
+	// fields holds the persistent structured context attached via With.
+	fields map[string]any
+
+	encoder   Encoder
+	colorMode string
+
+	async   *asyncWriter
+	sampler *sampler
+
+	// asyncEnabled/asyncBufSize/asyncOnDrop carry WithAsync's configuration
+	// until New constructs the asyncWriter, after the options loop has
+	// settled on a final l.w (e.g. WithFile may still run after WithAsync).
+	asyncEnabled bool
+	asyncBufSize int
+	asyncOnDrop  func(dropped uint64)
+
+	ctxExtractor ContextExtractor
+
+	// initErr carries a failure from an option that can fail (e.g.
+	// WithFile), surfaced by New rather than left to crash the logger on
+	// first use.
+	initErr error
 }
 
-// New
-func New(opts ...LoggerOption) *Logger {
+// New builds a Logger from opts. It returns an error if any option failed
+// to apply (e.g. WithFile couldn't open its target file).
+func New(opts ...LoggerOption) (*Logger, error) {
 	l := &Logger{
-		mu: sync.Mutex{},
+		mu:        &sync.Mutex{},
+		encoder:   JSONEncoder{},
+		colorMode: ColorAuto,
 	}
 
 	for _, opt := range opts {
 		opt(l)
 	}
 
-	return l
+	if l.initErr != nil {
+		return nil, l.initErr
+	}
+
+	if ce, ok := l.encoder.(ConsoleEncoder); ok {
+		ce.NoColor = !resolveColor(l.colorMode, l.w)
+		l.encoder = ce
+	}
+
+	if l.asyncEnabled {
+		l.async = newAsyncWriter(l.w, l.asyncBufSize, l.asyncOnDrop)
+	}
+
+	return l, nil
+}
+
+// With returns a child Logger that carries args as persistent structured
+// fields on every Entry it emits afterwards. args are interpreted as
+// alternating key/value pairs, or slog.Attr values. The child shares its
+// parent's mutex and writer. An odd-length kv list emits a "!BADKEY" field
+// instead of panicking.
+func (l *Logger) With(args ...any) *Logger {
+	return &Logger{
+		mu:            l.mu,
+		w:             l.w,
+		minLevel:      l.minLevel,
+		name:          l.name,
+		includeCaller: l.includeCaller,
+		stacktrace:    l.stacktrace,
+		fields:        mergeFields(l.fields, parseKV(args)),
+		encoder:       l.encoder,
+		colorMode:     l.colorMode,
+		async:         l.async,
+		sampler:       l.sampler,
+		ctxExtractor:  l.ctxExtractor,
+	}
+}
+
+// parseKV turns an alternating key/value argument list (or slog.Attr values)
+// into a fields map. A key that isn't a string, or a trailing key with no
+// value, is recorded under "!BADKEY" rather than panicking.
+func parseKV(args []any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]any, len(args)/2+1)
+
+	for i := 0; i < len(args); i++ {
+		if attr, ok := args[i].(slog.Attr); ok {
+			fields[attr.Key] = attr.Value.Any()
+			continue
+		}
+
+		if i+1 >= len(args) {
+			fields["!BADKEY"] = args[i]
+			break
+		}
+
+		key, ok := args[i].(string)
+		if !ok {
+			fields["!BADKEY"] = args[i]
+			continue
+		}
+
+		fields[key] = args[i+1]
+		i++
+	}
+
+	return fields
+}
+
+func mergeFields(parent, child map[string]any) map[string]any {
+	if len(parent) == 0 && len(child) == 0 {
+		return nil
+	}
+
+	merged := make(map[string]any, len(parent)+len(child))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range child {
+		merged[k] = v
+	}
+
+	return merged
 }
 
 // Debug
@@ -171,6 +285,12 @@ func (l *Logger) Debugf(format string, args ...any) {
 	l.logMsg(DEBUG, format, args...)
 }
 
+// Debugw logs msg at DEBUG with alternating key/value pairs attached as
+// structured fields alongside any fields carried by With.
+func (l *Logger) Debugw(msg string, keysAndValues ...any) {
+	l.logw(DEBUG, msg, keysAndValues...)
+}
+
 // Info
 func (l *Logger) Info(format string) {
 	l.logMsg(INFO, "%s", format)
@@ -181,6 +301,12 @@ func (l *Logger) Infof(format string, args ...any) {
 	l.logMsg(INFO, format, args...)
 }
 
+// Infow logs msg at INFO with alternating key/value pairs attached as
+// structured fields alongside any fields carried by With.
+func (l *Logger) Infow(msg string, keysAndValues ...any) {
+	l.logw(INFO, msg, keysAndValues...)
+}
+
 // Warn
 func (l *Logger) Warn(format string) {
 	l.logMsg(WARN, "%s", format)
@@ -191,6 +317,12 @@ func (l *Logger) Warnf(format string, args ...any) {
 	l.logMsg(WARN, format, args...)
 }
 
+// Warnw logs msg at WARN with alternating key/value pairs attached as
+// structured fields alongside any fields carried by With.
+func (l *Logger) Warnw(msg string, keysAndValues ...any) {
+	l.logw(WARN, msg, keysAndValues...)
+}
+
 // Error
 func (l *Logger) Error(format string) {
 	l.logMsg(ERROR, "%s", format)
@@ -201,6 +333,12 @@ func (l *Logger) Errorf(format string, args ...any) {
 	l.logMsg(ERROR, format, args...)
 }
 
+// Errorw logs msg at ERROR with alternating key/value pairs attached as
+// structured fields alongside any fields carried by With.
+func (l *Logger) Errorw(msg string, keysAndValues ...any) {
+	l.logw(ERROR, msg, keysAndValues...)
+}
+
 // Fatal
 func (l *Logger) Fatal(format string) {
 	l.logMsg(FATAL, "%s", format)
@@ -217,44 +355,88 @@ func (l *Logger) logMsg(level Level, format string, args ...any) {
 		return
 	}
 
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+
+	// FATAL must never be sampled away: dropping it would silently skip the
+	// os.Exit(1) in write, which is exactly the hazard zap's own sampler
+	// special-cases Fatal (and Panic) for.
+	if level != FATAL && l.sampler != nil && !l.sampler.allow(level, msg) {
+		return
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	logEntry := l.fmt(level, format, args...)
-	entrybytes, err := json.Marshal(&logEntry)
+	logEntry := l.fmt(level, msg)
+
+	l.write(&logEntry)
+}
+
+func (l *Logger) logw(level Level, msg string, keysAndValues ...any) {
+	if l.minLevel > level {
+		return
+	}
+
+	// FATAL must never be sampled away: dropping it would silently skip the
+	// os.Exit(1) in write, which is exactly the hazard zap's own sampler
+	// special-cases Fatal (and Panic) for.
+	if level != FATAL && l.sampler != nil && !l.sampler.allow(level, msg) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	logEntry := l.fmt(level, msg)
+	logEntry.Fields = mergeFields(logEntry.Fields, parseKV(keysAndValues))
+
+	l.write(&logEntry)
+}
+
+func (l *Logger) write(logEntry *Entry) {
+	entrybytes, err := l.encoder.Encode(*logEntry)
 	if err != nil {
-		fmt.Fprintf(l.w, "failed to marshal message %v original message %s", err, format) // nolint: errcheck
+		entrybytes = []byte(fmt.Sprintf("failed to marshal message %v original message %s", err, logEntry.Message))
 	}
 
 	entrybytes = append(entrybytes, '\n')
-	_, err = l.w.Write(entrybytes)
-	if err != nil {
-		fmt.Fprintf(l.w, "failed to write to out: %v", err) // nolint: errcheck
+
+	// Every write to l.w must go through this one path: when async is
+	// configured, its background goroutine also writes to l.w, and writing
+	// here directly (even just the encode-error fallback) would race with it.
+	if l.async != nil {
+		l.async.write(entrybytes)
+	} else {
+		_, err = l.w.Write(entrybytes)
+		if err != nil {
+			fmt.Fprintf(l.w, "failed to write to out: %v", err) // nolint: errcheck
+		}
 	}
 
-	if level == FATAL {
+	if logEntry.Level == FATAL.string() {
 		os.Exit(1)
 	}
 }
 
-func (l *Logger) fmt(level Level, format string, args ...any) entry {
+func (l *Logger) fmt(level Level, message string) Entry {
 	ts := time.Now().Format("2006-01-02 15:04:05")
 
-	var en entry
+	var en Entry
 	en.Level = level.string()
-	en.Message = format
-	en.Fields = make([]any, 0, len(args))
+	en.Message = message
 	en.Name = l.name
 	en.Timestamp = ts
 
-	for _, arg := range args {
-		en.Fields = append(en.Fields, arg)
-		if level == ERROR || level == FATAL {
-			if l.stacktrace {
-				stacktrace := debug.Stack()
-				en.Stacktrace = fmt.Sprintf(" %sstracktrace %s%s", colorFromLevel(level), colorReset, string(stacktrace))
-			}
-		}
+	if len(l.fields) > 0 {
+		en.Fields = mergeFields(l.fields, nil)
+	}
+
+	if (level == ERROR || level == FATAL) && l.stacktrace {
+		stacktrace := debug.Stack()
+		en.Stacktrace = fmt.Sprintf(" %sstracktrace %s%s", colorFromLevel(level), colorReset, string(stacktrace))
 	}
 
 	return en
@@ -2,11 +2,40 @@ package log_test
 
 import (
 	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/trevatk/log"
 )
 
+// slowWriter delays every Write, to let tests assert that Flush blocks
+// until a write has actually completed rather than merely dequeued.
+type slowWriter struct {
+	mu    sync.Mutex
+	delay time.Duration
+	buf   bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Len()
+}
+
 func TestDebug(t *testing.T) {
 
 	var buf bytes.Buffer
@@ -17,7 +46,10 @@ func TestDebug(t *testing.T) {
 		log.WithWriter(&buf),
 	}
 
-	logger := log.New(opts...)
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
 
 	logger.Debug("unformatted")
 	logger.Debugf("%d", 1)
@@ -39,7 +71,10 @@ func TestInfo(t *testing.T) {
 		log.WithWriter(&buf),
 	}
 
-	logger := log.New(opts...)
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
 
 	logger.Debug("unformatted")
 
@@ -67,7 +102,10 @@ func TestWarn(t *testing.T) {
 		log.WithWriter(&buf),
 	}
 
-	logger := log.New(opts...)
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
 
 	logger.Info("unformatted")
 
@@ -95,7 +133,10 @@ func TestError(t *testing.T) {
 		log.WithWriter(&buf),
 	}
 
-	logger := log.New(opts...)
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
 
 	logger.Info("unformatted")
 
@@ -112,3 +153,484 @@ func TestError(t *testing.T) {
 
 	t.Logf("%s", buf.String())
 }
+
+func TestWith(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithName("unit_test"),
+		log.WithWriter(&buf),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	child := logger.With("request_id", "abc123")
+
+	child.Infow("handled request", "status", 200)
+
+	if buf.Len() == 0 {
+		t.Fatal("buffer length is empty")
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"request_id":"abc123"`)) {
+		t.Fatalf("expected fields carried by With to be present, got %s", buf.String())
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"status":200`)) {
+		t.Fatalf("expected fields passed to Infow to be present, got %s", buf.String())
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithName("unit_test"),
+		log.WithWriter(&buf),
+		log.WithEncoder(log.LogfmtEncoder{}),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.With("request_id", "abc 123").Infow("handled request", "status", 200)
+
+	if !bytes.Contains(buf.Bytes(), []byte(`level=INFO`)) {
+		t.Fatalf("expected logfmt level pair, got %s", buf.String())
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte(`request_id="abc 123"`)) {
+		t.Fatalf("expected quoted value containing a space, got %s", buf.String())
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte(`status=200`)) {
+		t.Fatalf("expected unquoted numeric field, got %s", buf.String())
+	}
+}
+
+// customEncoder lives outside package log, proving WithEncoder's Encoder
+// interface can be implemented by third parties now that Entry is exported.
+type customEncoder struct{}
+
+func (customEncoder) Encode(e log.Entry) ([]byte, error) {
+	return []byte(e.Level + ": " + e.Message), nil
+}
+
+func TestCustomEncoder(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+		log.WithEncoder(customEncoder{}),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.Info("hello from outside the package")
+
+	want := "INFO: hello from outside the package\n"
+	if buf.String() != want {
+		t.Fatalf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestConsoleEncoder(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithName("unit_test"),
+		log.WithWriter(&buf),
+		log.WithEncoder(log.ConsoleEncoder{NoColor: true}),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.Infow("handled request", "status", 200)
+
+	want := "INFO unit_test handled request status=200"
+	if !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Fatalf("expected console line to contain %q, got %s", want, buf.String())
+	}
+}
+
+func TestConsoleEncoderFormatsLegacyAPI(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+		log.WithEncoder(log.ConsoleEncoder{NoColor: true}),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+
+	logger.Infof("user %s logged in with id %d", "alice", 42)
+	logger.Debugf("unreached") // below min level, should not appear
+
+	want := "INFO user alice logged in with id 42"
+	if !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Fatalf("expected Infof to be formatted via fmt.Sprintf, got %s", buf.String())
+	}
+
+	if bytes.Contains(buf.Bytes(), []byte("%s")) || bytes.Contains(buf.Bytes(), []byte("%d")) {
+		t.Fatalf("expected no raw format verbs in rendered output, got %s", buf.String())
+	}
+}
+
+func TestWithColorAlwaysEmitsEscapes(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+		log.WithEncoder(log.ConsoleEncoder{}),
+		log.WithColor(log.ColorAlways),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.Info("hello")
+
+	if !bytes.Contains(buf.Bytes(), []byte("\033[")) {
+		t.Fatalf("expected ANSI escape codes with ColorAlways, got %q", buf.String())
+	}
+}
+
+func TestWithColorNeverSuppressesEscapes(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+		log.WithEncoder(log.ConsoleEncoder{}),
+		log.WithColor(log.ColorNever),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.Info("hello")
+
+	if bytes.Contains(buf.Bytes(), []byte("\033[")) {
+		t.Fatalf("expected no ANSI escape codes with ColorNever, got %q", buf.String())
+	}
+}
+
+func TestAsync(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+		log.WithAsync(16, nil),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.Info("unformatted")
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error closing logger: %v", err)
+	}
+
+	if buf.Len() == 0 {
+		t.Fatal("buffer length is empty")
+	}
+}
+
+// TestAsyncTargetsFileSinkRegardlessOfOptionOrder guards against WithAsync
+// capturing whatever writer happens to be set when it runs, rather than the
+// final writer after all options (including WithFile) have applied.
+func TestAsyncTargetsFileSinkRegardlessOfOptionOrder(t *testing.T) {
+
+	dir := t.TempDir()
+	path := dir + "/test.log"
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithAsync(16, nil),
+		log.WithFile(path),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.Info("routed through the file sink")
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("unexpected error closing logger: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading log file: %v", err)
+	}
+	if len(b) == 0 {
+		t.Fatal("expected the async writer to have written to the file sink, got an empty file")
+	}
+}
+
+func TestAsyncFlushWaitsForWrite(t *testing.T) {
+
+	w := &slowWriter{delay: 50 * time.Millisecond}
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(w),
+		log.WithAsync(16, nil),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("unformatted")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := logger.Flush(ctx); err != nil {
+		t.Fatalf("unexpected error flushing: %v", err)
+	}
+
+	if w.Len() == 0 {
+		t.Fatal("expected Flush to block until the write actually completed")
+	}
+}
+
+func TestSampling(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+		log.WithSampling(time.Minute, 1, 0),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		logger.Info("repeated")
+	}
+
+	stats := logger.Stats()
+	if stats.SampledLogged != 1 {
+		t.Fatalf("expected 1 sampled log, got %d", stats.SampledLogged)
+	}
+	if stats.SampledDropped != 4 {
+		t.Fatalf("expected 4 dropped logs, got %d", stats.SampledDropped)
+	}
+}
+
+// TestFatalBypassesSampling guards against a dropped Fatal call silently
+// skipping os.Exit. It re-execs itself as a subprocess because Fatal exits
+// the process: the child configures sampling so that every call would
+// normally be dropped (first=0, thereafter=0), then calls Fatal once and
+// expects exit status 1 anyway.
+func TestFatalBypassesSampling(t *testing.T) {
+
+	if os.Getenv("LOG_TEST_FATAL_SAMPLING_CHILD") == "1" {
+		logger, err := log.New(
+			log.WithWriter(io.Discard),
+			log.WithSampling(time.Minute, 0, 0),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error creating logger: %v", err)
+		}
+		logger.Fatal("x")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestFatalBypassesSampling")
+	cmd.Env = append(os.Environ(), "LOG_TEST_FATAL_SAMPLING_CHILD=1")
+
+	err := cmd.Run()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		t.Fatalf("expected child process to exit with an error, got %v", err)
+	}
+	if exitErr.ExitCode() != 1 {
+		t.Fatalf("expected Fatal to exit 1 even when sampling would drop it, got exit code %d", exitErr.ExitCode())
+	}
+}
+
+func TestFileSinkRotation(t *testing.T) {
+
+	dir := t.TempDir()
+	path := dir + "/test.log"
+
+	fs, err := log.NewFileSink(path, log.WithMaxSize(8))
+	if err != nil {
+		t.Fatalf("unexpected error creating file sink: %v", err)
+	}
+	defer fs.Close()
+
+	if _, err := fs.Write([]byte("first line\n")); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if _, err := fs.Write([]byte("second line\n")); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("unexpected error globbing backups: %v", err)
+	}
+	if len(matches) == 0 {
+		t.Fatal("expected at least one rotated backup file")
+	}
+}
+
+func TestFileSinkRotationNoCollisions(t *testing.T) {
+
+	dir := t.TempDir()
+	path := dir + "/burst.log"
+
+	fs, err := log.NewFileSink(path, log.WithMaxSize(50), log.WithMaxBackups(1000))
+	if err != nil {
+		t.Fatalf("unexpected error creating file sink: %v", err)
+	}
+	defer fs.Close()
+
+	const writes = 200
+	for i := 0; i < writes; i++ {
+		if _, err := fs.Write([]byte("0123456789\n")); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("unexpected error globbing backups: %v", err)
+	}
+
+	seen := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		if seen[m] {
+			t.Fatalf("duplicate backup file path %q", m)
+		}
+		seen[m] = true
+	}
+
+	// Each 11-byte write fits four to a 50-byte file, so the 5th write and
+	// every 4th after it rotates: 49 rotations in 200 writes. A backup name
+	// collision would overwrite an earlier backup and leave fewer files.
+	const wantBackups = 49
+	if len(matches) != wantBackups {
+		t.Fatalf("expected %d distinct rotated backups from a write burst, got %d: %v", wantBackups, len(matches), matches)
+	}
+}
+
+func TestInfoCtx(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+		log.WithContextExtractor(func(ctx context.Context) []any {
+			return []any{"trace_id", ctx.Value(traceIDKey{})}
+		}),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "trace-123")
+	logger.InfoCtx(ctx, "handled request")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"trace_id":"trace-123"`)) {
+		t.Fatalf("expected trace_id pulled from context, got %s", buf.String())
+	}
+}
+
+type traceIDKey struct{}
+
+func TestInfoCtxCancelled(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	logger.InfoCtx(ctx, "handled request")
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"ctx_err"`)) {
+		t.Fatalf("expected ctx_err field for a cancelled context, got %s", buf.String())
+	}
+}
+
+func TestWithFileBadPath(t *testing.T) {
+
+	_, err := log.New(log.WithFile("/nonexistent-dir/app.log"))
+	if err == nil {
+		t.Fatal("expected an error for a file sink whose directory doesn't exist")
+	}
+}
+
+func TestWithOddArgs(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	opts := []log.LoggerOption{
+		log.WithLevel("INFO"),
+		log.WithWriter(&buf),
+	}
+
+	logger, err := log.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+	logger.Infow("odd args", "orphan_key")
+
+	if !bytes.Contains(buf.Bytes(), []byte("!BADKEY")) {
+		t.Fatalf("expected !BADKEY sentinel for odd-length kv list, got %s", buf.String())
+	}
+}
@@ -0,0 +1,123 @@
+package log
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// samplerShards bounds lock contention on the sampler's per-key counters;
+// each (level, msg) key is hashed into one of these shards.
+const samplerShards = 16
+
+// WithSampling applies zap-style sampling: within each tick window, the
+// first entries per distinct (level, msg) key are logged, and after that
+// only 1-of-thereafter. Set thereafter to 0 to drop everything past first
+// for the rest of the window.
+func WithSampling(tick time.Duration, first, thereafter int) LoggerOption {
+	return func(l *Logger) {
+		l.sampler = newSampler(tick, first, thereafter)
+	}
+}
+
+type sampleCounter struct {
+	resetAt int64 // unix nano, atomic
+	count   uint64
+}
+
+type sampler struct {
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+
+	shards [samplerShards]struct {
+		mu sync.Mutex
+		m  map[uint64]*sampleCounter
+	}
+
+	sampled uint64
+	dropped uint64
+}
+
+func newSampler(tick time.Duration, first, thereafter int) *sampler {
+	s := &sampler{
+		tick:       tick,
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+	}
+
+	for i := range s.shards {
+		s.shards[i].m = make(map[uint64]*sampleCounter)
+	}
+
+	return s
+}
+
+// allow reports whether an entry at level for msg should be logged, per the
+// sampling policy, and updates the policy's counters.
+func (s *sampler) allow(level Level, msg string) bool {
+	key := sampleKey(level, msg)
+	shard := &s.shards[key%samplerShards]
+
+	shard.mu.Lock()
+	c, ok := shard.m[key]
+	if !ok {
+		c = &sampleCounter{}
+		shard.m[key] = c
+	}
+	shard.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	if resetAt := atomic.LoadInt64(&c.resetAt); now > resetAt {
+		if atomic.CompareAndSwapInt64(&c.resetAt, resetAt, now+int64(s.tick)) {
+			atomic.StoreUint64(&c.count, 0)
+		}
+	}
+
+	n := atomic.AddUint64(&c.count, 1)
+
+	if n <= s.first {
+		atomic.AddUint64(&s.sampled, 1)
+		return true
+	}
+
+	if s.thereafter > 0 && (n-s.first)%s.thereafter == 0 {
+		atomic.AddUint64(&s.sampled, 1)
+		return true
+	}
+
+	atomic.AddUint64(&s.dropped, 1)
+	return false
+}
+
+func sampleKey(level Level, msg string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(level.string())) // nolint: errcheck
+	h.Write([]byte(msg))            // nolint: errcheck
+	return h.Sum64()
+}
+
+// Stats reports operational counters for the async writer and sampler, for
+// exporting to metrics.
+type Stats struct {
+	AsyncDropped   uint64
+	SampledLogged  uint64
+	SampledDropped uint64
+}
+
+// Stats returns a snapshot of the logger's async/sampling counters.
+func (l *Logger) Stats() Stats {
+	var st Stats
+
+	if l.async != nil {
+		st.AsyncDropped = atomic.LoadUint64(&l.async.dropped)
+	}
+
+	if l.sampler != nil {
+		st.SampledLogged = atomic.LoadUint64(&l.sampler.sampled)
+		st.SampledDropped = atomic.LoadUint64(&l.sampler.dropped)
+	}
+
+	return st
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler adapts a Logger to the slog.Handler interface.
+type slogHandler struct {
+	l *Logger
+}
+
+// AsSlogHandler adapts the Logger to slog.Handler so it can back a standard
+// library logger, e.g. slog.New(logger.AsSlogHandler()).
+func (l *Logger) AsSlogHandler() slog.Handler {
+	return &slogHandler{l: l}
+}
+
+// Enabled implements slog.Handler.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.minLevel <= levelFromSlog(level)
+}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	kv := make([]any, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		kv = append(kv, a)
+		return true
+	})
+
+	h.l.logw(levelFromSlog(record.Level), record.Message, kv...)
+
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	kv := make([]any, len(attrs))
+	for i, a := range attrs {
+		kv[i] = a
+	}
+
+	return &slogHandler{l: h.l.With(kv...)}
+}
+
+// WithGroup implements slog.Handler. Groups are not modeled; attrs added
+// under a group are attached flat, ungrouped.
+func (h *slogHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+func levelFromSlog(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return ERROR
+	case level >= slog.LevelWarn:
+		return WARN
+	case level >= slog.LevelInfo:
+		return INFO
+	default:
+		return DEBUG
+	}
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// errEncoder always fails to encode, forcing write's encode-error fallback
+// path, which used to bypass the async writer and race with its background
+// goroutine on the shared writer.
+type errEncoder struct{}
+
+func (errEncoder) Encode(Entry) ([]byte, error) {
+	return nil, errors.New("encode always fails")
+}
+
+func TestWriteEncodeErrorFallbackDoesNotRaceAsyncWriter(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	l, err := New(
+		WithWriter(&buf),
+		WithEncoder(errEncoder{}),
+		WithAsync(16, nil),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		l.Info("x")
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("unexpected error closing logger: %v", err)
+	}
+}